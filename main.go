@@ -2,12 +2,17 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ANSI 颜色常量
@@ -26,9 +31,115 @@ func runGitCommand(args ...string) (string, error) {
 	return string(output), err
 }
 
-// 验证工单号格式 (必须 bcds-<数字> 或 bcds-<数字>-xxx)
-func validateWorkItem(workItem string) bool {
-	re := regexp.MustCompile(`^bcds-\d+(-[a-z0-9]+)*$`)
+// isZeroSHA 判断给定的提交号是否为 pre-receive 用来表示分支创建/删除的全零 SHA
+func isZeroSHA(sha string) bool {
+	if sha == "" {
+		return false
+	}
+	for _, c := range sha {
+		if c != '0' {
+			return false
+		}
+	}
+	return true
+}
+
+// locale 表示交互界面使用的语言
+type locale string
+
+const (
+	LocaleZH locale = "zh"
+	LocaleEN locale = "en"
+)
+
+// messages 是按消息 id 和语言组织的文案表，覆盖交互流程中的全部提示与错误信息
+var messages = map[string]map[locale]string{
+	"banner":                           {LocaleZH: "=== Git 提交助手 (Custom Commit CLI) ===", LocaleEN: "=== Git Commit Assistant (Custom Commit CLI) ==="},
+	"noStagedChanges":                  {LocaleZH: "错误：没有暂存的更改，请先使用 'git add' 添加文件。", LocaleEN: "Error: no staged changes, please run 'git add' first."},
+	"promptWorkItem":                   {LocaleZH: "1. 请输入工单号 (格式: bcds-<数字> 或 bcds-<数字>-xxx): ", LocaleEN: "1. Enter the work item id (format: bcds-<number> or bcds-<number>-xxx): "},
+	"invalidWorkItem":                  {LocaleZH: "工单号格式无效，请重新输入。", LocaleEN: "Invalid work item id, please try again."},
+	"invalidWorkItemNonInteractive":    {LocaleZH: "工单号格式无效: %s", LocaleEN: "Invalid work item id: %s"},
+	"ticketOffline":                    {LocaleZH: "无法连接工单系统，已降级为离线校验: %s", LocaleEN: "Could not reach the ticket system, falling back to offline validation: %s"},
+	"ticketAssigneeWarning":            {LocaleZH: "警告: 工单 %s 当前分配给 %s，而非当前用户 %s", LocaleEN: "Warning: ticket %s is assigned to %s, not the current user %s"},
+	"ticketStateWarning":               {LocaleZH: "警告: 工单 %s 当前状态为 %s，不是 open", LocaleEN: "Warning: ticket %s is in state %s, not open"},
+	"ticketNonCompliantNonInteractive": {LocaleZH: "工单 %s 未分配给当前用户或未处于 open 状态，拒绝提交", LocaleEN: "Ticket %s is not assigned to the current user or not in an open state, refusing to commit"},
+	"ticketNonCompliantConfirm":        {LocaleZH: "工单归属/状态校验未通过，仍要继续提交吗？(Y/N): ", LocaleEN: "Ticket assignee/state check failed, continue anyway? (Y/N): "},
+	"ticketNonCompliantAborted":        {LocaleZH: "已取消提交。", LocaleEN: "Commit aborted."},
+	"ticketFillPrompt":                 {LocaleZH: "是否使用工单标题作为简短描述？[%s] (Y/N): ", LocaleEN: "Use the ticket title as the subject? [%s] (Y/N): "},
+	"promptCommitTypeHeader":           {LocaleZH: "2. 请选择提交类型:", LocaleEN: "2. Select a commit type:"},
+	"promptCommitTypeChoice":           {LocaleZH: "   请输入编号 (1-%d): ", LocaleEN: "   Enter a number (1-%d): "},
+	"invalidChoice":                    {LocaleZH: "请输入有效的数字 (1-%d)\n", LocaleEN: "Please enter a valid number (1-%d)\n"},
+	"invalidCommitTypeNonInteractive":  {LocaleZH: "提交类型无效: %s", LocaleEN: "Invalid commit type: %s"},
+	"promptSubjectHeader":              {LocaleZH: "3. 请输入简短描述（英文，首字母大写，不超过%d个字符）:", LocaleEN: "3. Enter a short subject (capitalized, up to %d characters):"},
+	"promptSubjectInput":               {LocaleZH: "   描述: ", LocaleEN: "   Subject: "},
+	"invalidSubject":                   {LocaleZH: "描述不符合规范，请确保使用英文、首字母大写且不超过%d个字符。\n", LocaleEN: "Invalid subject, make sure it is capitalized and at most %d characters.\n"},
+	"invalidSubjectNonInteractive":     {LocaleZH: "简短描述不符合规范: %s", LocaleEN: "Invalid subject: %s"},
+	"promptBodyHeader":                 {LocaleZH: "4. 输入详细描述（可选，多行，支持中文）", LocaleEN: "4. Enter a detailed description (optional, multi-line)"},
+	"multilineHint":                    {LocaleZH: "(直接回车结束):", LocaleEN: "(press Enter on an empty line to finish):"},
+	"promptBreaking":                   {LocaleZH: "5. 如果存在破坏性变更，请描述（可选，直接回车跳过）: ", LocaleEN: "5. Describe the breaking change, if any (optional, press Enter to skip): "},
+	"promptCloses":                     {LocaleZH: "6. 输入本次提交关闭的工单，多个以逗号分隔（可选，如 #123, bcds-456）: ", LocaleEN: "6. Enter issues closed by this commit, comma separated (optional, e.g. #123, bcds-456): "},
+	"renderedHeader":                   {LocaleZH: "生成的提交信息:", LocaleEN: "Generated commit message:"},
+	"confirmPrompt":                    {LocaleZH: "是否确认提交? (Y/N): ", LocaleEN: "Proceed with this commit? (Y/N): "},
+	"cancelled":                        {LocaleZH: "提交已取消。", LocaleEN: "Commit cancelled."},
+	"commitFailed":                     {LocaleZH: "提交失败: %s\n", LocaleEN: "Commit failed: %s\n"},
+	"successHeader":                    {LocaleZH: "=== 提交成功 ===", LocaleEN: "=== Commit succeeded ==="},
+	"successBody":                      {LocaleZH: "提交内容:\n%s\n", LocaleEN: "Commit message:\n%s\n"},
+	"pushHint":                         {LocaleZH: "可以使用 'git push' 推送更改。", LocaleEN: "Run 'git push' to publish your changes."},
+}
+
+// t 按当前语言渲染一条消息，缺失翻译时回退到中文
+func t(loc locale, key string, args ...interface{}) string {
+	tpl, ok := messages[key][loc]
+	if !ok {
+		tpl = messages[key][LocaleZH]
+	}
+	if len(args) == 0 {
+		return tpl
+	}
+	return fmt.Sprintf(tpl, args...)
+}
+
+// detectLocale 通过 LC_ALL/LANG 环境变量探测语言，未设置时默认中文
+func detectLocale() locale {
+	for _, env := range []string{"LC_ALL", "LANG"} {
+		v := strings.ToLower(os.Getenv(env))
+		if v == "" {
+			continue
+		}
+		if strings.HasPrefix(v, "zh") {
+			return LocaleZH
+		}
+		return LocaleEN
+	}
+	return LocaleZH
+}
+
+// localizeCommitType 在英文语言环境下，从形如 "中文描述 (English description)" 的文案中提取英文部分
+func localizeCommitType(description string, loc locale) string {
+	if loc != LocaleEN {
+		return description
+	}
+	re := regexp.MustCompile(`\(([^)]+)\)\s*$`)
+	if m := re.FindStringSubmatch(description); len(m) == 2 {
+		return m[1]
+	}
+	return description
+}
+
+// 验证工单号/范围 (默认必须 bcds-<数字> 或 bcds-<数字>-xxx，也可由配置文件覆盖为固定的 scopes 列表)
+func validateWorkItem(workItem string, cfg Config) bool {
+	if len(cfg.Scopes) > 0 {
+		for _, scope := range cfg.Scopes {
+			if scope == workItem {
+				return true
+			}
+		}
+		return false
+	}
+	re, err := regexp.Compile(cfg.WorkItemPattern)
+	if err != nil {
+		return false
+	}
 	return re.MatchString(workItem)
 }
 
@@ -38,36 +149,356 @@ type CommitType struct {
 	description string
 }
 
-// 获取提交类型列表（有序）
-func getCommitTypes() []CommitType {
-	return []CommitType{
-		{"feat", "新功能 (A new feature)"},
-		{"fix", "Bug修复 (A bug fix)"},
-		{"docs", "文档更新 (Documentation only changes)"},
-		{"style", "代码格式 (Changes that do not affect code meaning)"},
-		{"refactor", "代码重构 (Neither fixes bug nor adds feature)"},
-		{"perf", "性能优化 (A code change that improves performance)"},
-		{"test", "测试相关 (Adding or correcting tests)"},
-		{"build", "构建相关 (Affect build system or dependencies)"},
-		{"ci", "CI配置 (Changes to CI configuration files)"},
-		{"chore", "其他杂项 (Other changes)"},
+// 获取提交类型列表（有序），来自配置文件或默认值
+func getCommitTypes(cfg Config) []CommitType {
+	var types []CommitType
+	for _, ct := range cfg.Types {
+		types = append(types, CommitType{ct.Code, ct.Description})
 	}
+	return types
 }
 
-// 验证英文描述
-func validateEnglishDescription(desc string) bool {
-	if len(desc) == 0 || len(desc) > 72 {
+// 验证简短描述
+func validateEnglishDescription(desc string, cfg Config, loc locale, langForced bool) bool {
+	maxLen := cfg.MaxSubjectLength
+	if maxLen <= 0 {
+		maxLen = 72
+	}
+	if len(desc) == 0 || len([]rune(desc)) > maxLen {
 		return false
 	}
+	if cfg.EnglishOnly != nil && !*cfg.EnglishOnly {
+		return true
+	}
 	// 首字母必须大写
 	// if !strings.HasPrefix(strings.ToUpper(desc[:1]), desc[:1]) {
 	// 	return false
 	// }
+	// 在自动检测到英文语言环境（而非通过 --lang=en 显式指定）时，放宽为允许 Unicode 字母，
+	// 避免纯 ASCII 正则对混合团队造成误判；显式指定 --lang=en 时保留历史的纯英文校验。
+	if loc == LocaleEN && !langForced {
+		re := regexp.MustCompile(`^[\p{L}\p{N} ,.!?\-()]+$`)
+		return re.MatchString(desc)
+	}
 	// 只允许英文和基本标点
-	re := regexp.MustCompile(`^[A-Za-z0-9 ,.!?\-$begin:math:text$$end:math:text$]+$`)
+	re := regexp.MustCompile(`^[A-Za-z0-9 ,.!?\-()]+$`)
 	return re.MatchString(desc)
 }
 
+// ConfigCommitType 是配置文件中自定义提交类型的结构
+type ConfigCommitType struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// Config 描述可在 .commit-guide.yaml/.json 中覆盖的项目级配置
+type Config struct {
+	Types              []ConfigCommitType `json:"types"`
+	Scopes             []string           `json:"scopes"`
+	WorkItemPattern    string             `json:"workItemPattern"`
+	MaxSubjectLength   int                `json:"maxSubjectLength"`
+	ShowBody           *bool              `json:"showBody"`
+	ShowBreakingChange *bool              `json:"showBreakingChange"`
+	EnglishOnly        *bool              `json:"englishOnly"`
+	TicketAPI          string             `json:"ticketApi"`
+}
+
+// defaultConfig 返回内置的默认配置（与历史上的硬编码行为保持一致）
+func defaultConfig() Config {
+	t, bc, eo := true, true, true
+	return Config{
+		Types: []ConfigCommitType{
+			{"feat", "新功能 (A new feature)"},
+			{"fix", "Bug修复 (A bug fix)"},
+			{"docs", "文档更新 (Documentation only changes)"},
+			{"style", "代码格式 (Changes that do not affect code meaning)"},
+			{"refactor", "代码重构 (Neither fixes bug nor adds feature)"},
+			{"perf", "性能优化 (A code change that improves performance)"},
+			{"test", "测试相关 (Adding or correcting tests)"},
+			{"build", "构建相关 (Affect build system or dependencies)"},
+			{"ci", "CI配置 (Changes to CI configuration files)"},
+			{"chore", "其他杂项 (Other changes)"},
+		},
+		WorkItemPattern:    `^bcds-\d+(-[a-z0-9]+)*$`,
+		MaxSubjectLength:   72,
+		ShowBody:           &t,
+		ShowBreakingChange: &bc,
+		EnglishOnly:        &eo,
+	}
+}
+
+// loadConfig 在仓库根目录查找 .commit-guide.json/.yaml/.yml，叠加到默认配置上
+func loadConfig() Config {
+	cfg := defaultConfig()
+
+	root, err := runGitCommand("rev-parse", "--show-toplevel")
+	if err != nil {
+		return cfg
+	}
+	root = strings.TrimSpace(root)
+
+	for _, name := range []string{".commit-guide.json", ".commit-guide.yaml", ".commit-guide.yml"} {
+		data, err := os.ReadFile(root + "/" + name)
+		if err != nil {
+			continue
+		}
+
+		var loaded Config
+		if strings.HasSuffix(name, ".json") {
+			err = json.Unmarshal(data, &loaded)
+		} else {
+			err = parseYAMLConfig(data, &loaded)
+		}
+		if err != nil {
+			fmt.Printf(ColorRed+"解析配置文件 %s 失败，使用默认配置: %s\n"+ColorReset, name, err)
+			return cfg
+		}
+
+		mergeConfig(&cfg, loaded)
+		return cfg
+	}
+
+	return cfg
+}
+
+// mergeConfig 将配置文件中显式设置的字段叠加到默认配置上
+func mergeConfig(base *Config, loaded Config) {
+	if len(loaded.Types) > 0 {
+		base.Types = loaded.Types
+	}
+	if len(loaded.Scopes) > 0 {
+		base.Scopes = loaded.Scopes
+	}
+	if loaded.WorkItemPattern != "" {
+		base.WorkItemPattern = loaded.WorkItemPattern
+	}
+	if loaded.MaxSubjectLength > 0 {
+		base.MaxSubjectLength = loaded.MaxSubjectLength
+	}
+	if loaded.ShowBody != nil {
+		base.ShowBody = loaded.ShowBody
+	}
+	if loaded.ShowBreakingChange != nil {
+		base.ShowBreakingChange = loaded.ShowBreakingChange
+	}
+	if loaded.EnglishOnly != nil {
+		base.EnglishOnly = loaded.EnglishOnly
+	}
+	if loaded.TicketAPI != "" {
+		base.TicketAPI = loaded.TicketAPI
+	}
+}
+
+// parseYAMLConfig 解析 .commit-guide.yaml 支持的最小 YAML 子集（无第三方依赖）
+func parseYAMLConfig(data []byte, cfg *Config) error {
+	lines := strings.Split(string(data), "\n")
+	indented := func(s string) bool {
+		return s != "" && (strings.HasPrefix(s, " ") || strings.HasPrefix(s, "\t")) && strings.TrimSpace(s) != ""
+	}
+	entry := func(s string) (string, string) {
+		k, v, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(s, "- ")), ":")
+		return strings.TrimSpace(k), strings.Trim(strings.TrimSpace(v), `"'`)
+	}
+
+	i := 0
+	for i < len(lines) {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || indented(lines[i]) {
+			i++
+			continue
+		}
+
+		key, rest, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			i++
+			continue
+		}
+		key, rest = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(rest), `"'`)
+		i++
+
+		switch key {
+		case "types":
+			for i < len(lines) && indented(lines[i]) {
+				item := strings.TrimSpace(lines[i])
+				if strings.HasPrefix(item, "- ") {
+					var ct ConfigCommitType
+					k, v := entry(item)
+					if k == "code" {
+						ct.Code = v
+					}
+					i++
+					for i < len(lines) && indented(lines[i]) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "- ") {
+						k, v := entry(strings.TrimSpace(lines[i]))
+						if k == "code" {
+							ct.Code = v
+						} else if k == "description" {
+							ct.Description = v
+						}
+						i++
+					}
+					cfg.Types = append(cfg.Types, ct)
+				} else {
+					i++
+				}
+			}
+		case "scopes":
+			if strings.HasPrefix(rest, "[") {
+				for _, part := range strings.Split(strings.Trim(rest, "[]"), ",") {
+					if s := strings.Trim(strings.TrimSpace(part), `"'`); s != "" {
+						cfg.Scopes = append(cfg.Scopes, s)
+					}
+				}
+			}
+			for i < len(lines) && indented(lines[i]) {
+				if s := strings.Trim(strings.TrimPrefix(strings.TrimSpace(lines[i]), "- "), `"'`); s != "" {
+					cfg.Scopes = append(cfg.Scopes, s)
+				}
+				i++
+			}
+		case "workItemPattern":
+			cfg.WorkItemPattern = rest
+		case "ticketApi":
+			cfg.TicketAPI = rest
+		case "maxSubjectLength":
+			if n, err := strconv.Atoi(rest); err == nil {
+				cfg.MaxSubjectLength = n
+			}
+		case "showBody":
+			v := rest == "true"
+			cfg.ShowBody = &v
+		case "showBreakingChange":
+			v := rest == "true"
+			cfg.ShowBreakingChange = &v
+		case "englishOnly":
+			v := rest == "true"
+			cfg.EnglishOnly = &v
+		}
+	}
+
+	return nil
+}
+
+// Ticket 描述从工单系统查询到的工单元数据
+type Ticket struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Assignee string `json:"assignee"`
+	State    string `json:"state"`
+}
+
+// TicketProvider 是工单系统的查询接口，便于替换为离线/测试实现
+type TicketProvider interface {
+	FetchTicket(workItem string) (*Ticket, error)
+}
+
+// httpTicketProvider 是 TicketProvider 的默认 HTTP 实现，对接配置中的 REST 端点
+type httpTicketProvider struct {
+	endpoint string
+	client   *http.Client
+}
+
+// newHTTPTicketProvider 创建一个指向给定 REST 端点的工单查询器
+func newHTTPTicketProvider(endpoint string) *httpTicketProvider {
+	return &httpTicketProvider{endpoint: endpoint, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// FetchTicket 向 `<endpoint>/<workItem>` 发起请求并解析出工单元数据
+func (p *httpTicketProvider) FetchTicket(workItem string) (*Ticket, error) {
+	resp, err := p.client.Get(strings.TrimRight(p.endpoint, "/") + "/" + workItem)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("工单服务返回状态码 %d", resp.StatusCode)
+	}
+
+	var ticket Ticket
+	if err := json.NewDecoder(resp.Body).Decode(&ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// ticketCacheDir 返回工单缓存目录，遵循 XDG_CACHE_HOME 约定
+func ticketCacheDir() string {
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			base = home + "/.cache"
+		}
+	}
+	return base + "/git-commit-guide"
+}
+
+// cachedTicket 是写入磁盘的缓存条目，记录抓取时间用于判断是否过期
+type cachedTicket struct {
+	Ticket    Ticket `json:"ticket"`
+	FetchedAt int64  `json:"fetchedAt"`
+}
+
+// ticketCacheTTL 缓存有效期
+const ticketCacheTTL = 10 * time.Minute
+
+// loadCachedTicket 读取未过期的工单缓存
+func loadCachedTicket(workItem string) (*Ticket, bool) {
+	data, err := os.ReadFile(ticketCacheDir() + "/" + workItem + ".json")
+	if err != nil {
+		return nil, false
+	}
+
+	var cached cachedTicket
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, false
+	}
+	if time.Since(time.Unix(cached.FetchedAt, 0)) > ticketCacheTTL {
+		return nil, false
+	}
+	return &cached.Ticket, true
+}
+
+// saveCachedTicket 将工单写入缓存
+func saveCachedTicket(workItem string, ticket Ticket) {
+	dir := ticketCacheDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+	data, err := json.Marshal(cachedTicket{Ticket: ticket, FetchedAt: time.Now().Unix()})
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(dir+"/"+workItem+".json", data, 0o644)
+}
+
+// fetchTicket 查询工单，优先命中缓存，未命中时回源并写入缓存
+func fetchTicket(provider TicketProvider, workItem string) (*Ticket, error) {
+	if cached, ok := loadCachedTicket(workItem); ok {
+		return cached, nil
+	}
+
+	ticket, err := provider.FetchTicket(workItem)
+	if err != nil {
+		return nil, err
+	}
+
+	saveCachedTicket(workItem, *ticket)
+	return ticket, nil
+}
+
+// truncateSubject 按规则截断过长的工单标题，使其可作为提交简短描述
+func truncateSubject(s string, maxLen int) string {
+	if maxLen <= 0 {
+		maxLen = 72
+	}
+	r := []rune(s)
+	if len(r) <= maxLen {
+		return s
+	}
+	return string(r[:maxLen])
+}
+
 // 从控制台读取一行输入
 func readLine(prompt string) string {
 	scanner := bufio.NewScanner(os.Stdin)
@@ -77,8 +508,8 @@ func readLine(prompt string) string {
 }
 
 // 从控制台读取多行输入，空行结束
-func readMultiline(prompt string) string {
-	fmt.Println(prompt + " (直接回车结束):")
+func readMultiline(prompt string, loc locale) string {
+	fmt.Println(prompt + " " + t(loc, "multilineHint"))
 	scanner := bufio.NewScanner(os.Stdin)
 	var lines []string
 	for {
@@ -92,86 +523,518 @@ func readMultiline(prompt string) string {
 	return strings.Join(lines, "\n")
 }
 
-// 程序入口
+// 从控制台读取一个以逗号分隔的列表，空行返回 nil
+func readList(prompt string) []string {
+	raw := readLine(prompt)
+	if raw == "" {
+		return nil
+	}
+	var items []string
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+// CommitMessage 描述一条符合 Conventional Commits 规范的提交信息
+type CommitMessage struct {
+	commitType     string
+	workItem       string
+	subject        string
+	body           string
+	breakingChange string
+	closes         []string
+	refs           []string
+}
+
+// RenderCommitMessage 将 CommitMessage 渲染为最终的提交信息文本
+func RenderCommitMessage(msg CommitMessage) string {
+	marker := ""
+	if msg.breakingChange != "" {
+		marker = "!"
+	}
+	text := fmt.Sprintf("%s(%s)%s: %s", msg.commitType, msg.workItem, marker, msg.subject)
+
+	if msg.body != "" {
+		text = text + "\n\n" + msg.body
+	}
+
+	var footers []string
+	if msg.breakingChange != "" {
+		footers = append(footers, "BREAKING CHANGE: "+msg.breakingChange)
+	}
+	for _, issue := range msg.closes {
+		footers = append(footers, "Closes: "+issue)
+	}
+	if len(msg.refs) > 0 {
+		footers = append(footers, "Refs: "+strings.Join(msg.refs, ", "))
+	}
+	if len(footers) > 0 {
+		text = text + "\n\n" + strings.Join(footers, "\n")
+	}
+
+	return text
+}
+
+// 程序入口，按子命令分发
 func main() {
-	// 检查是否有暂存的更改
-	_, err := runGitCommand("diff", "--cached", "--quiet")
-	if err == nil {
-		fmt.Println(ColorRed + "错误：没有暂存的更改，请先使用 'git add' 添加文件。" + ColorReset)
+	// Git 调用 commit-msg 钩子时会以 `commit-msg <消息文件路径>` 执行符号链接指向的本二进制文件，
+	// 此时 os.Args[1] 是消息文件路径而非子命令名，需要通过可执行文件名来识别这种调用方式。
+	if filepath.Base(os.Args[0]) == "commit-msg" {
+		runCommitMsgHook(os.Args[1:])
+		return
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "hook":
+			runHook(os.Args[2:])
+			return
+		case "install-hooks":
+			runInstallHooks()
+			return
+		case "commit":
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+		}
+	}
+
+	runCommit()
+}
+
+// headerPattern 根据配置构建用于校验提交头的正则表达式
+func headerPattern(cfg Config) *regexp.Regexp {
+	var codes []string
+	for _, ct := range getCommitTypes(cfg) {
+		codes = append(codes, ct.code)
+	}
+
+	scopePattern := cfg.WorkItemPattern
+	if len(cfg.Scopes) > 0 {
+		scopePattern = "(" + strings.Join(cfg.Scopes, "|") + ")"
+	}
+	// 去掉锚点，因为 scope 只是整条正则中的一段
+	scopePattern = strings.TrimPrefix(strings.TrimSuffix(scopePattern, "$"), "^")
+
+	maxLen := cfg.MaxSubjectLength
+	if maxLen <= 0 {
+		maxLen = 72
+	}
+
+	pattern := fmt.Sprintf(`^(%s)\(%s\)!?: .{1,%d}$`, strings.Join(codes, "|"), scopePattern, maxLen)
+	return regexp.MustCompile(pattern)
+}
+
+// validateHeader 校验提交信息的第一行是否符合规范
+func validateHeader(header string, cfg Config) bool {
+	return headerPattern(cfg).MatchString(header)
+}
+
+// runHook 分发 commit-msg / pre-receive 两种 Git Hook 模式
+func runHook(args []string) {
+	if len(args) == 0 {
+		fmt.Println(ColorRed + "用法: git-commit-guide hook <commit-msg|pre-receive> [参数...]" + ColorReset)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "commit-msg":
+		runCommitMsgHook(args[1:])
+	case "pre-receive":
+		runPreReceiveHook()
+	default:
+		fmt.Printf(ColorRed+"未知的 hook 模式: %s\n"+ColorReset, args[0])
 		os.Exit(1)
 	}
+}
 
-	fmt.Println(ColorCyan + "=== Git 提交助手 (Custom Commit CLI) ===" + ColorReset)
+// runCommitMsgHook 实现 commit-msg 钩子：校验本地提交信息文件
+func runCommitMsgHook(args []string) {
+	if len(args) == 0 {
+		fmt.Println(ColorRed + "用法: git-commit-guide hook commit-msg <消息文件路径>" + ColorReset)
+		os.Exit(1)
+	}
 
-	// 1. 输入并验证工单号
-	var workItem string
-	for {
-		workItem = strings.ToLower(readLine(ColorYellow + "1. 请输入工单号 (格式: bcds-<数字> 或 bcds-<数字>-xxx): " + ColorReset))
-		if validateWorkItem(workItem) {
-			break
+	content, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Printf(ColorRed+"无法读取提交信息文件: %s\n"+ColorReset, err)
+		os.Exit(1)
+	}
+
+	cfg := loadConfig()
+	header := strings.SplitN(string(content), "\n", 2)[0]
+	if !validateHeader(header, cfg) {
+		fmt.Println(ColorRed + "提交信息头不符合规范:" + ColorReset)
+		fmt.Println("  " + header)
+		fmt.Println(ColorYellow + "期望格式: <type>(<bcds-工单号>)!?: <简短描述>" + ColorReset)
+		os.Exit(1)
+	}
+}
+
+// runPreReceiveHook 实现 pre-receive 钩子：校验服务端收到的每一次推送
+func runPreReceiveHook() {
+	cfg := loadConfig()
+	scanner := bufio.NewScanner(os.Stdin)
+	rejected := false
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		oldRev, newRev, refName := fields[0], fields[1], fields[2]
+
+		// 删除分支时 newRev 为全零，没有新提交需要校验
+		if isZeroSHA(newRev) {
+			continue
+		}
+
+		var output string
+		var err error
+		if isZeroSHA(oldRev) {
+			// 新建分支时 oldRev 为全零，改为校验所有现有引用都不可达的提交
+			output, err = runGitCommand("log", "--format=%H %s", newRev, "--not", "--all")
+		} else {
+			output, err = runGitCommand("log", "--format=%H %s", oldRev+".."+newRev)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, ColorRed+"无法读取 %s 的提交记录: %s\n"+ColorReset, refName, output)
+			rejected = true
+			continue
 		}
-		fmt.Println(ColorRed + "工单号格式无效，请重新输入。" + ColorReset)
+
+		for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			parts := strings.SplitN(line, " ", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			sha, subject := parts[0], parts[1]
+			if !validateHeader(subject, cfg) {
+				fmt.Fprintf(os.Stderr, ColorRed+"拒绝推送: 提交 %s 的信息不符合规范: %q\n"+ColorReset, sha, subject)
+				rejected = true
+			}
+		}
+	}
+
+	if rejected {
+		os.Exit(1)
 	}
+}
 
-	// 2. 选择提交类型
-	commitTypes := getCommitTypes()
-	fmt.Println("\n" + ColorYellow + "2. 请选择提交类型:" + ColorReset)
-	for i, ct := range commitTypes {
-		fmt.Printf("   %d. %-8s %s\n", i+1, ct.code, ct.description)
+// runInstallHooks 将当前可执行文件安装为仓库的 commit-msg 钩子
+func runInstallHooks() {
+	exePath, err := os.Executable()
+	if err != nil {
+		fmt.Printf(ColorRed+"无法定位当前可执行文件: %s\n"+ColorReset, err)
+		os.Exit(1)
 	}
 
-	var commitType string
-	for {
-		input := readLine(fmt.Sprintf("   请输入编号 (1-%d): ", len(commitTypes)))
-		choice, err := strconv.Atoi(input)
-		if err == nil && choice >= 1 && choice <= len(commitTypes) {
-			commitType = commitTypes[choice-1].code
-			break
+	gitDir, err := runGitCommand("rev-parse", "--git-dir")
+	if err != nil {
+		fmt.Printf(ColorRed+"无法定位 .git 目录: %s\n"+ColorReset, gitDir)
+		os.Exit(1)
+	}
+	gitDir = strings.TrimSpace(gitDir)
+
+	hookPath := gitDir + "/hooks/commit-msg"
+	os.Remove(hookPath)
+	if err := os.Symlink(exePath, hookPath); err != nil {
+		fmt.Printf(ColorRed+"安装 commit-msg 钩子失败: %s\n"+ColorReset, err)
+		os.Exit(1)
+	}
+
+	fmt.Println(ColorGreen + "已安装 commit-msg 钩子: " + hookPath + ColorReset)
+}
+
+// isKnownCommitType 判断给定的提交类型是否在允许的类型列表中
+func isKnownCommitType(commitType string, commitTypes []CommitType) bool {
+	for _, ct := range commitTypes {
+		if ct.code == commitType {
+			return true
 		}
-		fmt.Printf(ColorRed+"请输入有效的数字 (1-%d)\n"+ColorReset, len(commitTypes))
 	}
+	return false
+}
+
+// parseCommitMessageHeader 从完整提交信息中解析出 type/scope/subject，解析失败时均返回空字符串
+func parseCommitMessageHeader(message string) (commitType, workItem, subject string) {
+	lines := strings.SplitN(message, "\n", 2)
+	re := regexp.MustCompile(`^(\w+)\(([^)]+)\)!?: (.+)$`)
+	m := re.FindStringSubmatch(lines[0])
+	if m == nil {
+		return "", "", ""
+	}
+	return m[1], m[2], m[3]
+}
 
-	// 3. 输入并验证英文简短描述
-	fmt.Println("\n" + ColorYellow + "3. 请输入简短描述（英文，首字母大写，不超过72个字符）:" + ColorReset)
-	var description string
-	for {
-		description = readLine("   描述: ")
-		if validateEnglishDescription(description) {
+// footerLinePattern 匹配 RenderCommitMessage 生成的 footer 行 (BREAKING CHANGE/Closes/Refs)
+var footerLinePattern = regexp.MustCompile(`^(BREAKING CHANGE|Closes|Refs): (.+)$`)
+
+// parseCommitMessageBody 从完整的提交信息中还原 body 与 footer (BREAKING CHANGE/Closes)，
+// 供 --amend 完整回显上一次提交内容，而不只是 header 部分
+func parseCommitMessageBody(message string) (body, breakingChange string, closes []string) {
+	paragraphs := strings.Split(message, "\n\n")
+	if len(paragraphs) <= 1 {
+		return "", "", nil
+	}
+	bodyParagraphs := paragraphs[1:]
+
+	last := bodyParagraphs[len(bodyParagraphs)-1]
+	lastLines := strings.Split(last, "\n")
+	isFooterBlock := true
+	for _, line := range lastLines {
+		if !footerLinePattern.MatchString(line) {
+			isFooterBlock = false
 			break
 		}
-		fmt.Println(ColorRed + "描述不符合规范，请确保使用英文、首字母大写且不超过72个字符。" + ColorReset)
 	}
 
-	// 4. 输入可选的详细描述
-	body := readMultiline("\n" + ColorYellow + "4. 输入详细描述（可选，多行，支持中文）" + ColorReset)
+	if isFooterBlock {
+		for _, line := range lastLines {
+			m := footerLinePattern.FindStringSubmatch(line)
+			switch m[1] {
+			case "BREAKING CHANGE":
+				breakingChange = m[2]
+			case "Closes":
+				closes = append(closes, m[2])
+			}
+		}
+		bodyParagraphs = bodyParagraphs[:len(bodyParagraphs)-1]
+	}
+
+	body = strings.Join(bodyParagraphs, "\n\n")
+	return body, breakingChange, closes
+}
 
-	// 构建提交信息
-	commitMessage := fmt.Sprintf("%s(%s): %s", commitType, workItem, description)
-	if body != "" {
-		commitMessage = commitMessage + "\n\n" + body
+// runCommit 生成并执行一次提交，支持交互式与 --type/--scope/--subject/--body 非交互式两种模式
+func runCommit() {
+	fs := flag.NewFlagSet("commit", flag.ExitOnError)
+	amend := fs.Bool("amend", false, "修改上一次提交 (prefill 自 git log -1)")
+	sign := fs.Bool("S", false, "对提交进行 GPG 签名")
+	noVerify := fs.Bool("no-verify", false, "提交时跳过本地 Git Hooks")
+	typeFlag := fs.String("type", "", "提交类型 (非交互模式)")
+	scopeFlag := fs.String("scope", "", "工单号/范围 (非交互模式)")
+	subjectFlag := fs.String("subject", "", "简短描述 (非交互模式)")
+	bodyFlag := fs.String("body", "", "详细描述 (非交互模式)")
+	offline := fs.Bool("offline", false, "跳过工单系统查询，仅做本地格式校验")
+	langFlag := fs.String("lang", "", "界面语言 en|zh，默认根据 LANG/LC_ALL 自动探测")
+	fs.Parse(os.Args[1:])
+
+	cfg := loadConfig()
+
+	loc := detectLocale()
+	langForced := false
+	if *langFlag == "en" {
+		loc, langForced = LocaleEN, true
+	} else if *langFlag == "zh" {
+		loc, langForced = LocaleZH, true
+	}
+
+	// 检查是否有暂存的更改（--amend 时允许没有新的暂存更改）
+	if !*amend {
+		_, err := runGitCommand("diff", "--cached", "--quiet")
+		if err == nil {
+			fmt.Println(ColorRed + t(loc, "noStagedChanges") + ColorReset)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println(ColorCyan + t(loc, "banner") + ColorReset)
+
+	commitType, workItem, description, body := *typeFlag, strings.ToLower(*scopeFlag), *subjectFlag, *bodyFlag
+	var breakingChange string
+	var closes []string
+
+	if *amend {
+		prevMessage, err := runGitCommand("log", "-1", "--pretty=%B")
+		if err == nil {
+			trimmed := strings.TrimRight(prevMessage, "\n")
+			prevType, prevScope, prevSubject := parseCommitMessageHeader(trimmed)
+			prevBody, prevBreakingChange, prevCloses := parseCommitMessageBody(trimmed)
+			if commitType == "" {
+				commitType = prevType
+			}
+			if workItem == "" {
+				workItem = strings.ToLower(prevScope)
+			}
+			if description == "" {
+				description = prevSubject
+			}
+			if body == "" {
+				body = prevBody
+			}
+			if breakingChange == "" {
+				breakingChange = prevBreakingChange
+			}
+			if len(closes) == 0 {
+				closes = prevCloses
+			}
+		}
 	}
 
-	fmt.Println("\n" + ColorGreen + "生成的提交信息:" + ColorReset)
+	nonInteractive := *typeFlag != "" && *scopeFlag != "" && *subjectFlag != ""
+
+	// 1. 工单号
+	if !validateWorkItem(workItem, cfg) {
+		if nonInteractive {
+			fmt.Println(ColorRed + t(loc, "invalidWorkItemNonInteractive", workItem) + ColorReset)
+			os.Exit(1)
+		}
+		for {
+			workItem = strings.ToLower(readLine(ColorYellow + t(loc, "promptWorkItem") + ColorReset))
+			if validateWorkItem(workItem, cfg) {
+				break
+			}
+			fmt.Println(ColorRed + t(loc, "invalidWorkItem") + ColorReset)
+		}
+	}
+
+	// 1.1 查询工单系统，校验归属/状态并可用工单标题预填简短描述
+	var refs []string
+	if !*offline && cfg.TicketAPI != "" {
+		ticket, err := fetchTicket(newHTTPTicketProvider(cfg.TicketAPI), workItem)
+		if err != nil {
+			fmt.Println(ColorYellow + t(loc, "ticketOffline", err.Error()) + ColorReset)
+		} else {
+			emailOutput, _ := runGitCommand("config", "user.email")
+			email := strings.TrimSpace(emailOutput)
+
+			assigned := ticket.Assignee == "" || strings.EqualFold(ticket.Assignee, email)
+			open := ticket.State == "" || ticket.State == "open"
+
+			// 工单可达时，归属/状态校验是硬性要求：非交互模式下直接拒绝提交，
+			// 交互模式下需要用户显式确认才能继续，避免校验沦为摆设
+			if !assigned || !open {
+				if !assigned {
+					fmt.Println(ColorYellow + t(loc, "ticketAssigneeWarning", workItem, ticket.Assignee, email) + ColorReset)
+				}
+				if !open {
+					fmt.Println(ColorYellow + t(loc, "ticketStateWarning", workItem, ticket.State) + ColorReset)
+				}
+				if nonInteractive {
+					fmt.Println(ColorRed + t(loc, "ticketNonCompliantNonInteractive", workItem) + ColorReset)
+					os.Exit(1)
+				}
+				confirm := strings.ToLower(readLine(ColorYellow + t(loc, "ticketNonCompliantConfirm") + ColorReset))
+				if confirm != "y" && confirm != "yes" {
+					fmt.Println(ColorRed + t(loc, "ticketNonCompliantAborted") + ColorReset)
+					os.Exit(1)
+				}
+			}
+
+			if description == "" && ticket.Title != "" && !nonInteractive {
+				fill := strings.ToLower(readLine(t(loc, "ticketFillPrompt", truncateSubject(ticket.Title, cfg.MaxSubjectLength))))
+				if fill == "y" || fill == "yes" {
+					description = truncateSubject(ticket.Title, cfg.MaxSubjectLength)
+				}
+			}
+			refs = append(refs, workItem)
+		}
+	}
+
+	// 2. 提交类型
+	commitTypes := getCommitTypes(cfg)
+	if !isKnownCommitType(commitType, commitTypes) {
+		if nonInteractive {
+			fmt.Println(ColorRed + t(loc, "invalidCommitTypeNonInteractive", commitType) + ColorReset)
+			os.Exit(1)
+		}
+		fmt.Println("\n" + ColorYellow + t(loc, "promptCommitTypeHeader") + ColorReset)
+		for i, ct := range commitTypes {
+			fmt.Printf("   %d. %-8s %s\n", i+1, ct.code, localizeCommitType(ct.description, loc))
+		}
+		for {
+			input := readLine(t(loc, "promptCommitTypeChoice", len(commitTypes)))
+			choice, err := strconv.Atoi(input)
+			if err == nil && choice >= 1 && choice <= len(commitTypes) {
+				commitType = commitTypes[choice-1].code
+				break
+			}
+			fmt.Print(ColorRed + t(loc, "invalidChoice", len(commitTypes)) + ColorReset)
+		}
+	}
+
+	// 3. 简短描述
+	if !validateEnglishDescription(description, cfg, loc, langForced) {
+		if nonInteractive {
+			fmt.Println(ColorRed + t(loc, "invalidSubjectNonInteractive", description) + ColorReset)
+			os.Exit(1)
+		}
+		fmt.Println("\n" + ColorYellow + t(loc, "promptSubjectHeader", cfg.MaxSubjectLength) + ColorReset)
+		for {
+			description = readLine(t(loc, "promptSubjectInput"))
+			if validateEnglishDescription(description, cfg, loc, langForced) {
+				break
+			}
+			fmt.Print(ColorRed + t(loc, "invalidSubject", cfg.MaxSubjectLength) + ColorReset)
+		}
+	}
+
+	// 4. 详细描述
+	if body == "" && !nonInteractive && (cfg.ShowBody == nil || *cfg.ShowBody) {
+		body = readMultiline("\n"+ColorYellow+t(loc, "promptBodyHeader")+ColorReset, loc)
+	}
+
+	// 5. 破坏性变更说明
+	if breakingChange == "" && !nonInteractive && (cfg.ShowBreakingChange == nil || *cfg.ShowBreakingChange) {
+		breakingChange = readLine("\n" + ColorYellow + t(loc, "promptBreaking") + ColorReset)
+	}
+
+	// 6. 关联工单列表
+	if len(closes) == 0 && !nonInteractive {
+		closes = readList("\n" + ColorYellow + t(loc, "promptCloses") + ColorReset)
+	}
+
+	// 构建提交信息
+	commitMessage := RenderCommitMessage(CommitMessage{
+		commitType:     commitType,
+		workItem:       workItem,
+		subject:        description,
+		body:           body,
+		breakingChange: breakingChange,
+		closes:         closes,
+		refs:           refs,
+	})
+
+	fmt.Println("\n" + ColorGreen + t(loc, "renderedHeader") + ColorReset)
 	fmt.Println("--------------------------------------------------")
 	fmt.Println(commitMessage)
 	fmt.Println("--------------------------------------------------")
 
-	// 确认提交
-	confirm := strings.ToLower(readLine("是否确认提交? (Y/N): "))
-	if strings.ToLower(confirm) != "y" && strings.ToLower(confirm) != "yes" {
-		fmt.Println(ColorRed + "提交已取消。" + ColorReset)
-		os.Exit(0)
+	// 确认提交（非交互模式下直接提交）
+	if !nonInteractive {
+		confirm := strings.ToLower(readLine(t(loc, "confirmPrompt")))
+		if confirm != "y" && confirm != "yes" {
+			fmt.Println(ColorRed + t(loc, "cancelled") + ColorReset)
+			os.Exit(0)
+		}
 	}
 
 	// 执行提交
-	output, err := runGitCommand("commit", "-m", commitMessage)
+	args := []string{"commit", "-m", commitMessage}
+	if *amend {
+		args = append(args, "--amend")
+	}
+	if *sign {
+		args = append(args, "-S")
+	}
+	if *noVerify {
+		args = append(args, "--no-verify")
+	}
+
+	output, err := runGitCommand(args...)
 	if err != nil {
-		fmt.Printf(ColorRed+"提交失败: %s\n"+ColorReset, output)
+		fmt.Print(ColorRed + t(loc, "commitFailed", output) + ColorReset)
 		os.Exit(1)
 	}
 
 	// 提交成功
-	fmt.Println("\n" + ColorGreen + "=== 提交成功 ===" + ColorReset)
-	fmt.Printf("提交内容:\n%s\n", commitMessage)
-	fmt.Println("可以使用 'git push' 推送更改。")
+	fmt.Println("\n" + ColorGreen + t(loc, "successHeader") + ColorReset)
+	fmt.Print(t(loc, "successBody", commitMessage))
+	fmt.Println(t(loc, "pushHint"))
 }